@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// BinaryResolver locates the pg_dump/psql/pg_restore/pg_basebackup binaries
+// for a PostgreSQL major version. It checks SearchDirs (in order, with "%s"
+// substituted for the major version) before falling back to PATH, so
+// operators running on Alpine, RHEL, macOS/Homebrew, or with PGDG packages
+// installed under a non-Debian prefix don't have to fork the image just to
+// relocate the binaries.
+type BinaryResolver struct {
+	// SearchDirs are directory templates checked in order before falling
+	// back to PATH. A "%s" in a template is replaced with the major
+	// version; templates without one are checked as-is.
+	SearchDirs []string
+}
+
+// DefaultBinaryResolver is the BinaryResolver Client uses unless Resolver is
+// overridden. It covers the layouts of the official PGDG Debian/Ubuntu
+// packages, RHEL/CentOS PGDG RPMs, Homebrew on both Apple Silicon and Intel
+// Macs, and single-version installs (Alpine, most other distros) that put
+// everything on PATH.
+var DefaultBinaryResolver = &BinaryResolver{
+	SearchDirs: []string{
+		"/usr/lib/postgresql/%s/bin",
+		"/usr/pgsql-%s/bin",
+		"/opt/homebrew/opt/postgresql@%s/bin",
+		"/usr/local/opt/postgresql@%s/bin",
+	},
+}
+
+// Resolver is the BinaryResolver used to locate PostgreSQL binaries. Tests
+// and operators with nonstandard layouts may replace it.
+var Resolver = DefaultBinaryResolver
+
+// ErrBinaryNotFound is returned by BinaryResolver.Resolve when Binary could
+// not be located for MajorVersion, in any of SearchDirs or PATH. Searched
+// lists every path that was checked so the UI can show operators exactly
+// where to put (or symlink) the binary.
+type ErrBinaryNotFound struct {
+	Binary       string
+	MajorVersion string
+	Searched     []string
+}
+
+func (e *ErrBinaryNotFound) Error() string {
+	return fmt.Sprintf(
+		"%s for PostgreSQL %s not found; searched %s",
+		e.Binary, e.MajorVersion, strings.Join(e.Searched, ", "),
+	)
+}
+
+// Resolve locates binary (e.g. "pg_dump") for the given PostgreSQL major
+// version. Every candidate, whether found via SearchDirs or PATH, is
+// validated by running "<binary> --version" and checking that the reported
+// major version matches, so a same-named binary for the wrong PostgreSQL
+// version is never silently used.
+func (r *BinaryResolver) Resolve(majorVersion, binary string) (string, error) {
+	var searched []string
+
+	for _, dir := range r.SearchDirs {
+		if strings.Contains(dir, "%s") {
+			dir = fmt.Sprintf(dir, majorVersion)
+		}
+		path := filepath.Join(dir, binary)
+		searched = append(searched, path)
+
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		if binaryVersionMatches(path, majorVersion) {
+			return path, nil
+		}
+	}
+
+	if path, err := exec.LookPath(binary); err == nil {
+		searched = append(searched, path+" (PATH)")
+		if binaryVersionMatches(path, majorVersion) {
+			return path, nil
+		}
+	}
+
+	return "", &ErrBinaryNotFound{
+		Binary: binary, MajorVersion: majorVersion, Searched: searched,
+	}
+}
+
+// versionOutputRe extracts the major version from output of the form
+// "pg_dump (PostgreSQL) 16.3" or "pg_dump (PostgreSQL) 17devel".
+var versionOutputRe = regexp.MustCompile(`\)\s+(\d+)`)
+
+// binaryVersionMatches runs "path --version" and reports whether its
+// reported major version matches majorVersion. A binary that can't be run,
+// or whose output can't be parsed, is treated as a mismatch rather than an
+// error: Resolve simply keeps searching the remaining candidates.
+func binaryVersionMatches(path, majorVersion string) bool {
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return false
+	}
+
+	matches := versionOutputRe.FindStringSubmatch(string(output))
+	return matches != nil && matches[1] == majorVersion
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFakeBinary writes an executable shell script at path that prints
+// output to stdout and exits 0, mimicking "<binary> --version".
+func writeFakeBinary(t *testing.T, path, output string) {
+	t.Helper()
+	script := "#!/bin/sh\nprintf '" + output + "\\n'\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatalf("error writing fake binary: %v", err)
+	}
+}
+
+func TestBinaryResolverResolveFindsMatchingVersion(t *testing.T) {
+	root := t.TempDir()
+	binDir := filepath.Join(root, "16", "bin")
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		t.Fatalf("error creating fixture bin dir: %v", err)
+	}
+	writeFakeBinary(t, filepath.Join(binDir, "pg_dump"), "pg_dump (PostgreSQL) 16.3")
+
+	resolver := &BinaryResolver{SearchDirs: []string{filepath.Join(root, "%s", "bin")}}
+
+	path, err := resolver.Resolve("16", "pg_dump")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if want := filepath.Join(binDir, "pg_dump"); path != want {
+		t.Errorf("Resolve returned %q, want %q", path, want)
+	}
+}
+
+func TestBinaryResolverResolveSkipsVersionMismatch(t *testing.T) {
+	root := t.TempDir()
+	binDir := filepath.Join(root, "16", "bin")
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		t.Fatalf("error creating fixture bin dir: %v", err)
+	}
+	// A pg_dump binary that exists but reports the wrong major version.
+	writeFakeBinary(t, filepath.Join(binDir, "pg_dump"), "pg_dump (PostgreSQL) 15.1")
+
+	resolver := &BinaryResolver{SearchDirs: []string{filepath.Join(root, "%s", "bin")}}
+
+	_, err := resolver.Resolve("16", "pg_dump")
+	if err == nil {
+		t.Fatal("expected an error for a version mismatch, got nil")
+	}
+	if _, ok := err.(*ErrBinaryNotFound); !ok {
+		t.Errorf("error = %T(%v), want *ErrBinaryNotFound", err, err)
+	}
+}
+
+func TestBinaryResolverResolveNotFound(t *testing.T) {
+	resolver := &BinaryResolver{SearchDirs: []string{t.TempDir()}}
+
+	_, err := resolver.Resolve("16", "pg_dump_does_not_exist_anywhere")
+	if err == nil {
+		t.Fatal("expected an error when the binary can't be found, got nil")
+	}
+	if _, ok := err.(*ErrBinaryNotFound); !ok {
+		t.Errorf("error = %T(%v), want *ErrBinaryNotFound", err, err)
+	}
+}
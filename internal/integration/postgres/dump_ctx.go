@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ProgressEvent describes one step pg_dump --verbose reported while a
+// DumpCtx dump is running.
+type ProgressEvent struct {
+	// Object names the item pg_dump is working on, e.g. "table public.foo".
+	Object string
+
+	// Phase is the action pg_dump applied to Object, e.g. "dumping contents".
+	Phase string
+
+	// Rows is the row count pg_dump reported for Object, or 0 if the line
+	// didn't include one.
+	Rows int64
+}
+
+// DumpHandle is a running pg_dump started by DumpCtx. Unlike the io.Reader
+// Dump returns, a DumpHandle can be cancelled from the UI and reports its
+// progress as it runs.
+type DumpHandle struct {
+	reader   io.Reader
+	counter  *countingWriter
+	progress chan ProgressEvent
+	cancel   context.CancelFunc
+	done     chan struct{}
+	err      error
+}
+
+// Reader returns the dump stream, exactly as Dump's return value would.
+func (h *DumpHandle) Reader() io.Reader { return h.reader }
+
+// BytesWritten returns the number of dump bytes produced so far. Safe to
+// call concurrently with reads from Reader.
+func (h *DumpHandle) BytesWritten() int64 { return atomic.LoadInt64(&h.counter.n) }
+
+// Progress delivers a ProgressEvent for each object pg_dump reports
+// progress on. It's closed once the dump finishes, after which Wait
+// returns. Callers that only need Reader/BytesWritten/Wait are free to
+// never read from it: events are dropped, not blocked on, once
+// progressBufferSize of them are backlogged.
+func (h *DumpHandle) Progress() <-chan ProgressEvent { return h.progress }
+
+// Cancel aborts the running pg_dump. Wait then returns ctx.Err() wrapped
+// with the usual pg_dump error context.
+func (h *DumpHandle) Cancel() { h.cancel() }
+
+// Wait blocks until the dump finishes and returns its error, if any. It may
+// be called concurrently with reads from Reader, and is safe to call more
+// than once.
+func (h *DumpHandle) Wait() error {
+	<-h.done
+	return h.err
+}
+
+// countingWriter wraps an io.Writer, tracking the number of bytes written
+// through it. Unlike countingReader, its counter is updated atomically:
+// DumpHandle.BytesWritten reads it from a different goroutine than the one
+// writing.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	atomic.AddInt64(&c.n, int64(n))
+	return n, err
+}
+
+// progressBufferSize bounds DumpHandle.progress so that a caller who never
+// reads Progress() can't stall the stderr-scanning goroutine: any dump of a
+// single table produces at least one event, and a blocking, unbuffered send
+// there would wedge cmd.Wait()/writer.Close() behind it forever, hanging
+// Reader() at EOF and leaking the pg_dump process.
+const progressBufferSize = 64
+
+// DumpCtx runs pg_dump like Dump, but context-aware, cancelable, and with
+// progress events parsed from "--verbose" stderr output. It doesn't support
+// DumpFormatDirectory, which pg_dump can't stream through stdout.
+func (c *Client) DumpCtx(
+	ctx context.Context, version PGVersion, connString string, params ...DumpParams,
+) (*DumpHandle, error) {
+	pickedParams := DumpParams{}
+	if len(params) > 0 {
+		pickedParams = params[0]
+	}
+	if pickedParams.Format == DumpFormatDirectory {
+		return nil, fmt.Errorf(
+			"DumpCtx does not support DumpFormatDirectory; use Dump instead",
+		)
+	}
+	if pickedParams.Jobs > 0 {
+		return nil, fmt.Errorf(
+			"DumpParams.Jobs is only valid together with DumpFormatDirectory, " +
+				"which DumpCtx does not support",
+		)
+	}
+
+	pgDumpPath, err := version.Value.pgDump()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error locating pg_dump for v%s: %w", version.Value.version, err,
+		)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	args := append(dumpArgs(connString, pickedParams), "--verbose")
+	cmd := exec.CommandContext(ctx, pgDumpPath, args...)
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("error opening pg_dump stderr: %w", err)
+	}
+
+	reader, writer := io.Pipe()
+	counter := &countingWriter{w: writer}
+	cmd.Stdout = counter
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf(
+			"error starting pg_dump v%s: %w", version.Value.version, err,
+		)
+	}
+
+	handle := &DumpHandle{
+		reader:   reader,
+		counter:  counter,
+		progress: make(chan ProgressEvent, progressBufferSize),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	// cmd.Wait must not run until all reads from stderr have completed, so
+	// the scan runs to completion on its own goroutine and signals the
+	// waiter below through stderrDone rather than racing it.
+	stderrDone := make(chan struct{})
+	stderrLines := &strings.Builder{}
+	go func() {
+		defer close(stderrDone)
+
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			stderrLines.WriteString(line)
+			stderrLines.WriteByte('\n')
+
+			if event, ok := parseProgressLine(line); ok {
+				select {
+				case handle.progress <- event:
+				default:
+					// No one is draining Progress(), or it's backlogged
+					// past progressBufferSize: drop the event rather than
+					// block the scan and, with it, cmd.Wait().
+				}
+			}
+		}
+	}()
+
+	go func() {
+		defer cancel()
+		defer close(handle.done)
+		defer close(handle.progress)
+		defer writer.Close()
+
+		<-stderrDone
+		if err := cmd.Wait(); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				handle.err = fmt.Errorf(
+					"error running pg_dump v%s: %w", version.Value.version, ctxErr,
+				)
+			} else {
+				handle.err = fmt.Errorf(
+					"error running pg_dump v%s: %s", version.Value.version, stderrLines.String(),
+				)
+			}
+			writer.CloseWithError(handle.err)
+		}
+	}()
+
+	return handle, nil
+}
+
+// pgDumpVerboseLineRe matches pg_dump --verbose stderr lines of the form
+// "<phase> of|for <kind> "<schema>"."<name>"", e.g.:
+//
+//	pg_dump: dumping contents of table "public"."foo"
+//	pg_dump: reading indexes for table "public"."foo"
+//
+// Lines without "of"/"for" before the object, such as
+// `pg_dump: creating TABLE "public"."foo"`, don't match.
+var pgDumpVerboseLineRe = regexp.MustCompile(
+	`^pg_dump:\s+([a-zA-Z ]+?)\s+(?:of|for)\s+([a-zA-Z]+)\s+"([^"]+)"\."([^"]+)"`,
+)
+
+// pgDumpRowCountRe extracts a row count from progress lines that report
+// one, e.g. `pg_dump: dumping contents of table "public"."foo" (12345 rows)`.
+var pgDumpRowCountRe = regexp.MustCompile(`\((\d+)\s+rows?\)`)
+
+// parseProgressLine extracts a ProgressEvent from one line of pg_dump
+// --verbose stderr output. Lines that aren't about a specific object (e.g.
+// "pg_dump: reading schemas") don't match and are ignored.
+func parseProgressLine(line string) (ProgressEvent, bool) {
+	matches := pgDumpVerboseLineRe.FindStringSubmatch(line)
+	if matches == nil {
+		return ProgressEvent{}, false
+	}
+
+	event := ProgressEvent{
+		Phase:  strings.TrimSpace(matches[1]),
+		Object: fmt.Sprintf("%s %s.%s", strings.ToLower(matches[2]), matches[3], matches[4]),
+	}
+
+	if rows := pgDumpRowCountRe.FindStringSubmatch(line); rows != nil {
+		if n, err := strconv.ParseInt(rows[1], 10, 64); err == nil {
+			event.Rows = n
+		}
+	}
+
+	return event, true
+}
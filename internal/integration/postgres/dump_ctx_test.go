@@ -0,0 +1,50 @@
+package postgres
+
+import "testing"
+
+func TestParseProgressLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want ProgressEvent
+		ok   bool
+	}{
+		{
+			name: "dumping contents with row count",
+			line: `pg_dump: dumping contents of table "public"."foo" (12345 rows)`,
+			want: ProgressEvent{Phase: "dumping contents", Object: "table public.foo", Rows: 12345},
+			ok:   true,
+		},
+		{
+			name: "reading indexes without row count",
+			line: `pg_dump: reading indexes for table "public"."foo"`,
+			want: ProgressEvent{Phase: "reading indexes", Object: "table public.foo"},
+			ok:   true,
+		},
+		{
+			name: "line with no object is ignored",
+			line: `pg_dump: reading schemas`,
+			ok:   false,
+		},
+		{
+			name: "creating line without of/for is ignored",
+			line: `pg_dump: creating TABLE "public"."foo"`,
+			ok:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseProgressLine(tt.line)
+			if ok != tt.ok {
+				t.Fatalf("parseProgressLine(%q) ok = %v, want %v", tt.line, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("parseProgressLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
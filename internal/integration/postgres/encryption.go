@@ -0,0 +1,375 @@
+package postgres
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/orsinium-labs/enum"
+	"golang.org/x/crypto/argon2"
+)
+
+// encryptionAlgo holds the display name for an Encryption member.
+type encryptionAlgo struct {
+	name string
+}
+
+// Encryption selects the at-rest encryption applied to a dump stream before
+// it's written into DumpZip's archive.
+type Encryption enum.Member[encryptionAlgo]
+
+var (
+	// EncryptionNone leaves the dump unencrypted. The zero value
+	// Encryption{} is treated the same way.
+	EncryptionNone = Encryption{encryptionAlgo{name: "none"}}
+
+	// EncryptionAgeX25519 encrypts with the `age` CLI against one or more
+	// X25519 recipients (DumpParams.Recipients).
+	EncryptionAgeX25519 = Encryption{encryptionAlgo{name: "age-x25519"}}
+
+	// EncryptionGPG encrypts with the `gpg` CLI using a symmetric
+	// passphrase (DumpParams.Passphrase).
+	EncryptionGPG = Encryption{encryptionAlgo{name: "gpg"}}
+
+	// EncryptionAES256GCM encrypts in-process with AES-256-GCM, using a key
+	// derived from DumpParams.Passphrase via argon2id.
+	EncryptionAES256GCM = Encryption{encryptionAlgo{name: "aes-256-gcm"}}
+)
+
+// aesGCMChunkSize is the size of the plaintext chunks streamAESGCMEncrypt/
+// streamAESGCMDecrypt operate on. AES-GCM authenticates a bounded message,
+// so a large dump is framed into fixed-size chunks rather than encrypted in
+// one call.
+const aesGCMChunkSize = 64 * 1024
+
+// argon2SaltSize and argon2KeyLen size the salt and derived key used to
+// turn an EncryptionAES256GCM passphrase into an AES-256 key.
+const (
+	argon2SaltSize = 16
+	argon2KeyLen   = 32
+)
+
+// encryptStream wraps r so that reading from the result yields the
+// encrypted dump, per algo.
+func encryptStream(
+	algo Encryption, passphrase string, recipients []string, r io.Reader,
+) (io.Reader, error) {
+	switch algo {
+	case EncryptionNone, Encryption{}:
+		return r, nil
+	case EncryptionAgeX25519:
+		return pipeThroughCommand(r, "age", ageEncryptArgs(recipients))
+	case EncryptionGPG:
+		return gpgPipe(r, passphrase, []string{
+			"--batch", "--yes",
+			"--pinentry-mode", "loopback",
+			"--passphrase-fd", "3",
+			"--symmetric", "--cipher-algo", "AES256",
+		})
+	case EncryptionAES256GCM:
+		return streamAESGCMEncrypt(r, passphrase)
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm: %s", algo.Value.name)
+	}
+}
+
+// decryptStream is the inverse of encryptStream.
+func decryptStream(
+	algo Encryption, passphrase string, ageIdentity string, r io.Reader,
+) (io.Reader, error) {
+	switch algo {
+	case EncryptionNone, Encryption{}:
+		return r, nil
+	case EncryptionAgeX25519:
+		return ageDecrypt(r, ageIdentity)
+	case EncryptionGPG:
+		return gpgPipe(r, passphrase, []string{
+			"--batch", "--yes",
+			"--pinentry-mode", "loopback",
+			"--passphrase-fd", "3",
+			"--decrypt",
+		})
+	case EncryptionAES256GCM:
+		return streamAESGCMDecrypt(r, passphrase)
+	default:
+		return nil, fmt.Errorf("unsupported encryption algorithm: %s", algo.Value.name)
+	}
+}
+
+// encryptionName returns algo's display name, treating the zero value the
+// same as EncryptionNone.
+func encryptionName(algo Encryption) string {
+	if algo == (Encryption{}) {
+		return EncryptionNone.Value.name
+	}
+	return algo.Value.name
+}
+
+// parseEncryption looks up the Encryption member with the given display
+// name, as stored in a Manifest.
+func parseEncryption(name string) (Encryption, error) {
+	switch name {
+	case "", EncryptionNone.Value.name:
+		return EncryptionNone, nil
+	case EncryptionAgeX25519.Value.name:
+		return EncryptionAgeX25519, nil
+	case EncryptionGPG.Value.name:
+		return EncryptionGPG, nil
+	case EncryptionAES256GCM.Value.name:
+		return EncryptionAES256GCM, nil
+	default:
+		return Encryption{}, fmt.Errorf("unknown encryption algorithm in manifest: %s", name)
+	}
+}
+
+func ageEncryptArgs(recipients []string) []string {
+	args := make([]string, 0, len(recipients)*2)
+	for _, recipient := range recipients {
+		args = append(args, "-r", recipient)
+	}
+	return args
+}
+
+// pipeThroughCommand runs name with args, feeding r to its stdin and
+// returning its stdout as an io.Reader.
+func pipeThroughCommand(r io.Reader, name string, args []string) (io.Reader, error) {
+	reader, writer := io.Pipe()
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = r
+	cmd.Stdout = writer
+
+	go func() {
+		defer writer.Close()
+		errBuf := &bytes.Buffer{}
+		cmd.Stderr = errBuf
+		if err := cmd.Run(); err != nil {
+			writer.CloseWithError(fmt.Errorf("error running %s: %s", name, errBuf.String()))
+		}
+	}()
+
+	return reader, nil
+}
+
+// ageDecrypt shells out to `age --decrypt` using ageIdentity as the private
+// key. age's -i/--identity flag takes a path, not inline key material, and
+// stdin is already carrying the ciphertext, so ageIdentity is written to a
+// private (0600) temp file for the lifetime of the command instead.
+func ageDecrypt(r io.Reader, ageIdentity string) (io.Reader, error) {
+	identityFile, err := os.CreateTemp("", "pbw-age-identity-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating age identity file: %w", err)
+	}
+	identityPath := identityFile.Name()
+
+	if err := identityFile.Chmod(0o600); err != nil {
+		identityFile.Close()
+		os.Remove(identityPath)
+		return nil, fmt.Errorf("error securing age identity file: %w", err)
+	}
+	if _, err := identityFile.WriteString(ageIdentity); err != nil {
+		identityFile.Close()
+		os.Remove(identityPath)
+		return nil, fmt.Errorf("error writing age identity file: %w", err)
+	}
+	if err := identityFile.Close(); err != nil {
+		os.Remove(identityPath)
+		return nil, fmt.Errorf("error writing age identity file: %w", err)
+	}
+
+	reader, writer := io.Pipe()
+	cmd := exec.Command("age", "--decrypt", "-i", identityPath)
+	cmd.Stdin = r
+	cmd.Stdout = writer
+
+	go func() {
+		defer os.Remove(identityPath)
+		defer writer.Close()
+		errBuf := &bytes.Buffer{}
+		cmd.Stderr = errBuf
+		if err := cmd.Run(); err != nil {
+			writer.CloseWithError(fmt.Errorf("error running age: %s", errBuf.String()))
+		}
+	}()
+
+	return reader, nil
+}
+
+// gpgPipe shells out to gpg for passphrase-based (symmetric) encryption or
+// decryption. The passphrase is passed over an extra file descriptor rather
+// than argv or stdin, so it never appears in `ps` output or collides with
+// the dump stream on stdin.
+func gpgPipe(r io.Reader, passphrase string, args []string) (io.Reader, error) {
+	passphraseReader, passphraseWriter, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("error creating passphrase pipe: %w", err)
+	}
+
+	reader, writer := io.Pipe()
+
+	cmd := exec.Command("gpg", args...)
+	cmd.Stdin = r
+	cmd.Stdout = writer
+	cmd.ExtraFiles = []*os.File{passphraseReader}
+
+	go func() {
+		defer passphraseWriter.Close()
+		fmt.Fprint(passphraseWriter, passphrase)
+	}()
+
+	go func() {
+		defer writer.Close()
+		defer passphraseReader.Close()
+		errBuf := &bytes.Buffer{}
+		cmd.Stderr = errBuf
+		if err := cmd.Run(); err != nil {
+			writer.CloseWithError(fmt.Errorf("error running gpg: %s", errBuf.String()))
+		}
+	}()
+
+	return reader, nil
+}
+
+// streamAESGCMEncrypt derives a key from passphrase via argon2id, then
+// encrypts r in aesGCMChunkSize plaintext chunks, each with its own nonce.
+// The output is: a random salt, followed by a sequence of
+// [4-byte big-endian ciphertext length][ciphertext+tag] records, terminated
+// by a zero-length record.
+func streamAESGCMEncrypt(r io.Reader, passphrase string) (io.Reader, error) {
+	salt := make([]byte, argon2SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("error generating salt: %w", err)
+	}
+
+	gcm, err := newAESGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		defer writer.Close()
+
+		if _, err := writer.Write(salt); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+
+		buf := make([]byte, aesGCMChunkSize)
+		nonce := make([]byte, gcm.NonceSize())
+		var counter uint64
+
+		for {
+			n, readErr := io.ReadFull(r, buf)
+			if n > 0 {
+				binary.BigEndian.PutUint64(nonce[len(nonce)-8:], counter)
+				counter++
+
+				ciphertext := gcm.Seal(nil, nonce, buf[:n], nil)
+
+				var lenPrefix [4]byte
+				binary.BigEndian.PutUint32(lenPrefix[:], uint32(len(ciphertext)))
+				if _, err := writer.Write(lenPrefix[:]); err != nil {
+					writer.CloseWithError(err)
+					return
+				}
+				if _, err := writer.Write(ciphertext); err != nil {
+					writer.CloseWithError(err)
+					return
+				}
+			}
+
+			if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+				var end [4]byte
+				writer.Write(end[:])
+				return
+			}
+			if readErr != nil {
+				writer.CloseWithError(readErr)
+				return
+			}
+		}
+	}()
+
+	return reader, nil
+}
+
+// streamAESGCMDecrypt is the inverse of streamAESGCMEncrypt.
+func streamAESGCMDecrypt(r io.Reader, passphrase string) (io.Reader, error) {
+	salt := make([]byte, argon2SaltSize)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return nil, fmt.Errorf("error reading salt: %w", err)
+	}
+
+	gcm, err := newAESGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		defer writer.Close()
+
+		nonce := make([]byte, gcm.NonceSize())
+		var counter uint64
+
+		for {
+			var lenPrefix [4]byte
+			if _, err := io.ReadFull(r, lenPrefix[:]); err != nil {
+				writer.CloseWithError(fmt.Errorf("error reading chunk length: %w", err))
+				return
+			}
+
+			chunkLen := binary.BigEndian.Uint32(lenPrefix[:])
+			if chunkLen == 0 {
+				return
+			}
+
+			ciphertext := make([]byte, chunkLen)
+			if _, err := io.ReadFull(r, ciphertext); err != nil {
+				writer.CloseWithError(fmt.Errorf("error reading chunk: %w", err))
+				return
+			}
+
+			binary.BigEndian.PutUint64(nonce[len(nonce)-8:], counter)
+			counter++
+
+			plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+			if err != nil {
+				writer.CloseWithError(fmt.Errorf("error decrypting chunk: %w", err))
+				return
+			}
+
+			if _, err := writer.Write(plaintext); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	return reader, nil
+}
+
+func newAESGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), salt, 1, 64*1024, 4, argon2KeyLen)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating AES cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating GCM: %w", err)
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestStreamAESGCMRoundTrip(t *testing.T) {
+	plaintext := bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog\n"), 5000)
+
+	encrypted, err := streamAESGCMEncrypt(bytes.NewReader(plaintext), "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("streamAESGCMEncrypt: %v", err)
+	}
+
+	ciphertext, err := io.ReadAll(encrypted)
+	if err != nil {
+		t.Fatalf("error reading ciphertext: %v", err)
+	}
+	if bytes.Contains(ciphertext, []byte("the quick brown fox")) {
+		t.Fatal("ciphertext contains plaintext, encryption did nothing")
+	}
+
+	decrypted, err := streamAESGCMDecrypt(bytes.NewReader(ciphertext), "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("streamAESGCMDecrypt: %v", err)
+	}
+
+	got, err := io.ReadAll(decrypted)
+	if err != nil {
+		t.Fatalf("error reading decrypted plaintext: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("round trip mismatch: got %d bytes, want %d bytes", len(got), len(plaintext))
+	}
+}
+
+func TestStreamAESGCMDecryptWrongPassphrase(t *testing.T) {
+	plaintext := []byte("sensitive dump contents")
+
+	encrypted, err := streamAESGCMEncrypt(bytes.NewReader(plaintext), "correct horse battery staple")
+	if err != nil {
+		t.Fatalf("streamAESGCMEncrypt: %v", err)
+	}
+	ciphertext, err := io.ReadAll(encrypted)
+	if err != nil {
+		t.Fatalf("error reading ciphertext: %v", err)
+	}
+
+	decrypted, err := streamAESGCMDecrypt(bytes.NewReader(ciphertext), "wrong passphrase")
+	if err != nil {
+		t.Fatalf("streamAESGCMDecrypt: %v", err)
+	}
+	if _, err := io.ReadAll(decrypted); err == nil {
+		t.Fatal("expected an error decrypting with the wrong passphrase, got nil")
+	}
+}
+
+func TestEncryptionNameParseEncryptionRoundTrip(t *testing.T) {
+	algos := []Encryption{EncryptionNone, EncryptionAgeX25519, EncryptionGPG, EncryptionAES256GCM}
+
+	for _, algo := range algos {
+		name := encryptionName(algo)
+		got, err := parseEncryption(name)
+		if err != nil {
+			t.Fatalf("parseEncryption(%q): %v", name, err)
+		}
+		if got != algo {
+			t.Errorf("parseEncryption(encryptionName(%v)) = %v, want %v", algo, got, algo)
+		}
+	}
+
+	if _, err := parseEncryption("rot13"); err == nil {
+		t.Fatal("expected an error for an unknown encryption name, got nil")
+	}
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import "io"
+
+// manifestFileName is the name of the integrity/metadata entry DumpZip adds
+// to every archive it produces.
+const manifestFileName = "manifest.json"
+
+// Manifest describes a dump archived by DumpZip: enough to tell what it
+// contains and to verify it hasn't been corrupted or tampered with before
+// RestoreZip decrypts and feeds it to psql/pg_restore.
+type Manifest struct {
+	// PGVersion is the PostgreSQL major version the dump was taken from.
+	PGVersion string `json:"pg_version"`
+
+	// Format is the DumpFormat the dump entry was written in (dumpFormat's
+	// stable name, e.g. "plain"/"custom"/"directory"/"tar"), as produced by
+	// dumpFormatName and consumed by parseDumpFormat.
+	Format string `json:"format"`
+
+	// Params records the non-secret DumpParams the dump was taken with.
+	Params ManifestDumpParams `json:"params"`
+
+	// Encryption is the algorithm, if any, the dump entry is encrypted
+	// with.
+	Encryption string `json:"encryption"`
+
+	// PlaintextBytes is the length of the dump before encryption.
+	PlaintextBytes int64 `json:"plaintext_bytes"`
+
+	// CiphertextBytes is the length of the dump entry stored in the
+	// archive.
+	CiphertextBytes int64 `json:"ciphertext_bytes"`
+
+	// PlaintextSHA256 is the hex-encoded SHA-256 of the dump before
+	// encryption.
+	PlaintextSHA256 string `json:"plaintext_sha256"`
+
+	// CiphertextSHA256 is the hex-encoded SHA-256 of the dump entry stored
+	// in the archive.
+	CiphertextSHA256 string `json:"ciphertext_sha256"`
+}
+
+// ManifestDumpParams mirrors the non-secret fields of DumpParams for
+// provenance. Passphrase and Recipients are never persisted here: they're
+// secrets, and a Manifest travels alongside a dump that may sit in a
+// world-readable bucket.
+type ManifestDumpParams struct {
+	DataOnly   bool `json:"data_only"`
+	SchemaOnly bool `json:"schema_only"`
+	Clean      bool `json:"clean"`
+	IfExists   bool `json:"if_exists"`
+	Create     bool `json:"create"`
+	NoComments bool `json:"no_comments"`
+	Jobs       int  `json:"jobs,omitempty"`
+}
+
+// manifestDumpParams extracts the fields of params safe to persist in a
+// Manifest.
+func manifestDumpParams(params DumpParams) ManifestDumpParams {
+	return ManifestDumpParams{
+		DataOnly:   params.DataOnly,
+		SchemaOnly: params.SchemaOnly,
+		Clean:      params.Clean,
+		IfExists:   params.IfExists,
+		Create:     params.Create,
+		NoComments: params.NoComments,
+		Jobs:       params.Jobs,
+	}
+}
+
+// countingReader wraps an io.Reader, tracking the number of bytes read
+// through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -1,13 +1,19 @@
 package postgres
 
 import (
+	"archive/tar"
 	"archive/zip"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 
 	"github.com/orsinium-labs/enum"
 )
@@ -20,37 +26,104 @@ import (
 	Backing up a database from an old unsupported version should not be allowed.
 */
 
+// version identifies a PostgreSQL major version. The actual binary paths
+// are not stored here: they're looked up on demand through Resolver, which
+// lets operators relocate the binaries without forking the image.
 type version struct {
 	version string
-	pgDump  string
-	psql    string
+}
+
+// pgDump resolves the pg_dump binary for this version via Resolver.
+func (v version) pgDump() (string, error) { return Resolver.Resolve(v.version, "pg_dump") }
+
+// psql resolves the psql binary for this version via Resolver.
+func (v version) psql() (string, error) { return Resolver.Resolve(v.version, "psql") }
+
+// pgRestore resolves the pg_restore binary for this version via Resolver.
+func (v version) pgRestore() (string, error) { return Resolver.Resolve(v.version, "pg_restore") }
+
+// pgBasebackup resolves the pg_basebackup binary for this version via
+// Resolver.
+func (v version) pgBasebackup() (string, error) {
+	return Resolver.Resolve(v.version, "pg_basebackup")
 }
 
 type PGVersion enum.Member[version]
 
 var (
-	PG13 = PGVersion{version{
-		version: "13",
-		pgDump:  "/usr/lib/postgresql/13/bin/pg_dump",
-		psql:    "/usr/lib/postgresql/13/bin/psql",
-	}}
-	PG14 = PGVersion{version{
-		version: "14",
-		pgDump:  "/usr/lib/postgresql/14/bin/pg_dump",
-		psql:    "/usr/lib/postgresql/14/bin/psql",
-	}}
-	PG15 = PGVersion{version{
-		version: "15",
-		pgDump:  "/usr/lib/postgresql/15/bin/pg_dump",
-		psql:    "/usr/lib/postgresql/15/bin/psql",
-	}}
-	PG16 = PGVersion{version{
-		version: "16",
-		pgDump:  "/usr/lib/postgresql/16/bin/pg_dump",
-		psql:    "/usr/lib/postgresql/16/bin/psql",
-	}}
+	PG13 = PGVersion{version{version: "13"}}
+	PG14 = PGVersion{version{version: "14"}}
+	PG15 = PGVersion{version{version: "15"}}
+	PG16 = PGVersion{version{version: "16"}}
+	PG17 = PGVersion{version{version: "17"}}
 )
 
+// pgVersionsByString backs ParseVersion. Adding a new supported major
+// version only requires a new PGVersion var and an entry here.
+var pgVersionsByString = map[string]PGVersion{
+	PG13.Value.version: PG13,
+	PG14.Value.version: PG14,
+	PG15.Value.version: PG15,
+	PG16.Value.version: PG16,
+	PG17.Value.version: PG17,
+}
+
+// dumpFormat holds the pg_dump/pg_restore "-F" flag value and the stable
+// name stored in Manifest.Format for a DumpFormat.
+type dumpFormat struct {
+	flag string
+	name string
+}
+
+// DumpFormat selects the output format used by Dump/DumpZip and expected by
+// Restore.
+type DumpFormat enum.Member[dumpFormat]
+
+var (
+	// DumpFormatPlain (-F p) emits a plain-text SQL script. This is the
+	// pg_dump default and the only format psql can consume.
+	DumpFormatPlain = DumpFormat{dumpFormat{flag: "p", name: "plain"}}
+
+	// DumpFormatCustom (-F c) emits pg_restore's compressed, archive
+	// format. Supports selective restore and parallel jobs.
+	DumpFormatCustom = DumpFormat{dumpFormat{flag: "c", name: "custom"}}
+
+	// DumpFormatDirectory (-F d) emits one file per table/blob in a
+	// directory, which can be dumped and restored in parallel with --jobs.
+	DumpFormatDirectory = DumpFormat{dumpFormat{flag: "d", name: "directory"}}
+
+	// DumpFormatTar (-F t) emits a tar archive. Supports selective restore,
+	// but not parallel jobs.
+	DumpFormatTar = DumpFormat{dumpFormat{flag: "t", name: "tar"}}
+)
+
+// dumpFormatName returns format's stable identifier, as stored in
+// Manifest.Format, treating the zero value the same as DumpFormatPlain.
+func dumpFormatName(format DumpFormat) string {
+	if format == (DumpFormat{}) {
+		return DumpFormatPlain.Value.name
+	}
+	return format.Value.name
+}
+
+// parseDumpFormat looks up the DumpFormat with the given stable identifier,
+// as stored in a Manifest. An empty name (manifests written before this
+// field existed) is treated as DumpFormatPlain.
+func parseDumpFormat(name string) (DumpFormat, error) {
+	switch name {
+	case "", DumpFormatPlain.Value.name:
+		return DumpFormatPlain, nil
+	case DumpFormatCustom.Value.name:
+		return DumpFormatCustom, nil
+	case DumpFormatDirectory.Value.name:
+		return DumpFormatDirectory, nil
+	case DumpFormatTar.Value.name:
+		return DumpFormatTar, nil
+	default:
+		return DumpFormat{}, fmt.Errorf("unknown dump format in manifest: %s", name)
+	}
+}
+
 type Client struct{}
 
 func New() *Client {
@@ -60,23 +133,21 @@ func New() *Client {
 // ParseVersion returns the PGVersion enum member for the given PostgreSQL
 // version as a string.
 func (Client) ParseVersion(version string) (PGVersion, error) {
-	switch version {
-	case "13":
-		return PG13, nil
-	case "14":
-		return PG14, nil
-	case "15":
-		return PG15, nil
-	case "16":
-		return PG16, nil
-	default:
+	v, ok := pgVersionsByString[version]
+	if !ok {
 		return PGVersion{}, fmt.Errorf("pg version not allowed: %s", version)
 	}
+	return v, nil
 }
 
 // Ping tests the connection to the PostgreSQL database
 func (Client) Ping(version PGVersion, connString string) error {
-	cmd := exec.Command(version.Value.psql, connString, "-c", "SELECT 1;")
+	psqlPath, err := version.Value.psql()
+	if err != nil {
+		return fmt.Errorf("error locating psql for v%s: %w", version.Value.version, err)
+	}
+
+	cmd := exec.Command(psqlPath, connString, "-c", "SELECT 1;")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf(
@@ -118,11 +189,37 @@ type DumpParams struct {
 
 	// NoComments (--no-comments): Do not dump comments.
 	NoComments bool
+
+	// Format (-F/--format) selects the pg_dump output format. The zero
+	// value behaves as DumpFormatPlain.
+	Format DumpFormat
+
+	// Jobs (-j/--jobs): number of parallel dump workers. Only valid together
+	// with DumpFormatDirectory.
+	Jobs int
+
+	// Encryption selects the at-rest encryption DumpZip applies to the dump
+	// entry before writing it to the archive. The zero value behaves as
+	// EncryptionNone. Ignored by Dump, which always returns the plaintext
+	// stream.
+	Encryption Encryption
+
+	// Passphrase derives the encryption key for EncryptionAES256GCM (via
+	// argon2id) and is used as the symmetric passphrase for EncryptionGPG.
+	// Ignored otherwise.
+	Passphrase string
+
+	// Recipients holds age X25519 recipient public keys, used when
+	// Encryption is EncryptionAgeX25519.
+	Recipients []string
 }
 
-// Dump runs the pg_dump command with the given parameters. It returns the SQL
-// dump as an io.Reader.
-func (Client) Dump(
+// Dump runs the pg_dump command with the given parameters. It returns the
+// dump as an io.Reader: a plain-SQL, custom-format, or tar stream for
+// DumpFormatPlain/DumpFormatCustom/DumpFormatTar, or a tar archive of the
+// directory pg_dump produced for DumpFormatDirectory (the directory format
+// itself cannot be written to stdout).
+func (c *Client) Dump(
 	version PGVersion, connString string, params ...DumpParams,
 ) io.Reader {
 	pickedParams := DumpParams{}
@@ -130,50 +227,189 @@ func (Client) Dump(
 		pickedParams = params[0]
 	}
 
+	if pickedParams.Format == DumpFormatDirectory {
+		return c.dumpDirectory(version, connString, pickedParams)
+	}
+
+	errorBuffer := &bytes.Buffer{}
+	reader, writer := io.Pipe()
+
+	go func() {
+		defer writer.Close()
+
+		if pickedParams.Jobs > 0 {
+			writer.CloseWithError(fmt.Errorf(
+				"DumpParams.Jobs is only valid together with DumpFormatDirectory",
+			))
+			return
+		}
+
+		pgDumpPath, err := version.Value.pgDump()
+		if err != nil {
+			writer.CloseWithError(fmt.Errorf(
+				"error locating pg_dump for v%s: %w", version.Value.version, err,
+			))
+			return
+		}
+
+		args := dumpArgs(connString, pickedParams)
+		cmd := exec.Command(pgDumpPath, args...)
+		cmd.Stdout = writer
+		cmd.Stderr = errorBuffer
+		if err := cmd.Run(); err != nil {
+			writer.CloseWithError(fmt.Errorf(
+				"error running pg_dump v%s: %s",
+				version.Value.version, errorBuffer.String(),
+			))
+		}
+	}()
+
+	return reader
+}
+
+// dumpArgs builds the pg_dump CLI arguments shared by the streaming (plain/
+// custom/tar) and directory dump paths.
+func dumpArgs(connString string, params DumpParams) []string {
 	args := []string{connString}
-	if pickedParams.DataOnly {
+	if params.DataOnly {
 		args = append(args, "--data-only")
 	}
-	if pickedParams.SchemaOnly {
+	if params.SchemaOnly {
 		args = append(args, "--schema-only")
 	}
-	if pickedParams.Clean {
+	if params.Clean {
 		args = append(args, "--clean")
 	}
-	if pickedParams.IfExists {
+	if params.IfExists {
 		args = append(args, "--if-exists")
 	}
-	if pickedParams.Create {
+	if params.Create {
 		args = append(args, "--create")
 	}
-	if pickedParams.NoComments {
+	if params.NoComments {
 		args = append(args, "--no-comments")
 	}
+	if params.Format != (DumpFormat{}) && params.Format != DumpFormatPlain {
+		args = append(args, "-F", params.Format.Value.flag)
+	}
+	if params.Jobs > 0 {
+		args = append(args, "--jobs", strconv.Itoa(params.Jobs))
+	}
+	return args
+}
 
-	errorBuffer := &bytes.Buffer{}
+// dumpDirectory runs pg_dump -F d (optionally parallelized with --jobs)
+// against a temporary directory, then streams a tar archive of that
+// directory back to the caller.
+func (Client) dumpDirectory(
+	version PGVersion, connString string, params DumpParams,
+) io.Reader {
 	reader, writer := io.Pipe()
-	cmd := exec.Command(version.Value.pgDump, args...)
-	cmd.Stdout = writer
-	cmd.Stderr = errorBuffer
 
 	go func() {
 		defer writer.Close()
+
+		dir, err := os.MkdirTemp("", "pbw-dump-*")
+		if err != nil {
+			writer.CloseWithError(fmt.Errorf("error creating temp dir: %w", err))
+			return
+		}
+		defer os.RemoveAll(dir)
+
+		dumpDir := filepath.Join(dir, "dump")
+		args := append(dumpArgs(connString, params), "-f", dumpDir)
+
+		pgDumpPath, err := version.Value.pgDump()
+		if err != nil {
+			writer.CloseWithError(fmt.Errorf(
+				"error locating pg_dump for v%s: %w", version.Value.version, err,
+			))
+			return
+		}
+
+		errorBuffer := &bytes.Buffer{}
+		cmd := exec.Command(pgDumpPath, args...)
+		cmd.Stderr = errorBuffer
 		if err := cmd.Run(); err != nil {
 			writer.CloseWithError(fmt.Errorf(
 				"error running pg_dump v%s: %s",
 				version.Value.version, errorBuffer.String(),
 			))
+			return
+		}
+
+		if err := tarDirectory(writer, dumpDir); err != nil {
+			writer.CloseWithError(fmt.Errorf("error taring dump directory: %w", err))
+			return
 		}
 	}()
 
 	return reader
 }
 
+// tarDirectory writes the contents of dir to w as a tar archive, with
+// entry names relative to dir.
+func tarDirectory(w io.Writer, dir string) error {
+	tarWriter := tar.NewWriter(w)
+	defer tarWriter.Close()
+
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = relPath
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+}
+
+// dumpZipEntryName returns the filename under which the dump is stored
+// inside the ZIP archive produced by DumpZip, based on its format.
+func dumpZipEntryName(format DumpFormat) string {
+	switch format {
+	case DumpFormatCustom:
+		return "dump.dump"
+	case DumpFormatDirectory, DumpFormatTar:
+		return "dump.tar"
+	default:
+		return "dump.sql"
+	}
+}
+
 // DumpZip runs the pg_dump command with the given parameters and returns the
-// ZIP-compressed SQL dump as an io.Reader.
+// ZIP-compressed dump as an io.Reader.
 func (c *Client) DumpZip(
 	version PGVersion, connString string, params ...DumpParams,
 ) io.Reader {
+	pickedParams := DumpParams{}
+	if len(params) > 0 {
+		pickedParams = params[0]
+	}
+
 	dumpReader := c.Dump(version, connString, params...)
 	reader, writer := io.Pipe()
 
@@ -183,26 +419,82 @@ func (c *Client) DumpZip(
 		zipWriter := zip.NewWriter(writer)
 		defer zipWriter.Close()
 
-		fileWriter, err := zipWriter.Create("dump.sql")
+		fileWriter, err := zipWriter.Create(dumpZipEntryName(pickedParams.Format))
 		if err != nil {
 			writer.CloseWithError(fmt.Errorf("error creating zip file: %w", err))
 			return
 		}
 
-		if _, err := io.Copy(fileWriter, dumpReader); err != nil {
+		plainCounter := &countingReader{r: dumpReader}
+		plainHash := sha256.New()
+
+		encrypted, err := encryptStream(
+			pickedParams.Encryption, pickedParams.Passphrase, pickedParams.Recipients,
+			io.TeeReader(plainCounter, plainHash),
+		)
+		if err != nil {
+			writer.CloseWithError(fmt.Errorf("error encrypting dump: %w", err))
+			return
+		}
+
+		cipherHash := sha256.New()
+		cipherBytes, err := io.Copy(fileWriter, io.TeeReader(encrypted, cipherHash))
+		if err != nil {
 			writer.CloseWithError(fmt.Errorf("error writing to zip file: %w", err))
 			return
 		}
+
+		manifestWriter, err := zipWriter.Create(manifestFileName)
+		if err != nil {
+			writer.CloseWithError(fmt.Errorf("error creating manifest: %w", err))
+			return
+		}
+
+		manifest := Manifest{
+			PGVersion:        version.Value.version,
+			Format:           dumpFormatName(pickedParams.Format),
+			Params:           manifestDumpParams(pickedParams),
+			Encryption:       encryptionName(pickedParams.Encryption),
+			PlaintextBytes:   plainCounter.n,
+			CiphertextBytes:  cipherBytes,
+			PlaintextSHA256:  hex.EncodeToString(plainHash.Sum(nil)),
+			CiphertextSHA256: hex.EncodeToString(cipherHash.Sum(nil)),
+		}
+		if err := json.NewEncoder(manifestWriter).Encode(manifest); err != nil {
+			writer.CloseWithError(fmt.Errorf("error writing manifest: %w", err))
+			return
+		}
 	}()
 
 	return reader
 }
 
+// RestoreZipOptions carries the secrets needed to decrypt an archive
+// produced with DumpParams.Encryption set.
+type RestoreZipOptions struct {
+	// Passphrase decrypts dumps produced with Encryption set to
+	// EncryptionGPG or EncryptionAES256GCM.
+	Passphrase string
+
+	// AgeIdentity is the age private key used to decrypt dumps produced
+	// with Encryption set to EncryptionAgeX25519.
+	AgeIdentity string
+}
+
 // RestoreZip downloads the ZIP from the given url, unzips it, and runs the
-// psql command to restore the database.
+// psql command (DumpFormatPlain) or, for the other formats recorded in the
+// archive's manifest, pg_restore to restore the database. It's kept for restoring dumps
+// served directly over HTTP(S); for dumps held in a BackupStore, use
+// RestoreFromStore instead, which streams the dump into psql/pg_restore
+// without the download-then-unzip-to-disk round trip.
 func (Client) RestoreZip(
-	version PGVersion, connString string, zipURL string,
+	version PGVersion, connString string, zipURL string, opts ...RestoreZipOptions,
 ) error {
+	pickedOpts := RestoreZipOptions{}
+	if len(opts) > 0 {
+		pickedOpts = opts[0]
+	}
+
 	// Create a temporary directory
 	dir, err := os.MkdirTemp("", "pbw-restore-*")
 	if err != nil {
@@ -235,42 +527,246 @@ func (Client) RestoreZip(
 	}
 	defer zipReadCloser.Close()
 
-	var dumpPath string
+	var rawPath string
+	var manifest *Manifest
 	for _, file := range zipReadCloser.File {
-		if file.Name == "dump.sql" {
-			dumpPath = fmt.Sprintf("%s/%s", dir, file.Name)
-
-			fileReadCloser, err := file.Open()
+		if file.Name == manifestFileName {
+			manifest, err = readManifest(file)
 			if err != nil {
-				return fmt.Errorf("error opening dump.sql in ZIP file: %w", err)
+				return err
 			}
-			defer fileReadCloser.Close()
+			continue
+		}
 
-			outFile, err := os.Create(dumpPath)
-			if err != nil {
-				return fmt.Errorf("error creating dump.sql: %w", err)
-			}
-			defer outFile.Close()
+		rawPath = filepath.Join(dir, file.Name)
 
-			if _, err = io.Copy(outFile, fileReadCloser); err != nil {
-				return fmt.Errorf("error writing dump.sql: %w", err)
-			}
+		fileReadCloser, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("error opening %q in ZIP file: %w", file.Name, err)
+		}
+		defer fileReadCloser.Close()
 
-			break
+		outFile, err := os.Create(rawPath)
+		if err != nil {
+			return fmt.Errorf("error creating %q: %w", file.Name, err)
+		}
+		defer outFile.Close()
+
+		if _, err = io.Copy(outFile, fileReadCloser); err != nil {
+			return fmt.Errorf("error writing %q: %w", file.Name, err)
 		}
 	}
 
-	if dumpPath == "" {
-		return fmt.Errorf("dump.sql not found in ZIP file")
+	if rawPath == "" {
+		return fmt.Errorf("dump not found in ZIP file")
+	}
+
+	dumpPath := rawPath
+	if manifest != nil {
+		dumpPath, err = decryptManifestedDump(dir, rawPath, manifest, pickedOpts)
+		if err != nil {
+			return err
+		}
+	}
+
+	format := DumpFormatPlain
+	if manifest != nil {
+		format, err = parseDumpFormat(manifest.Format)
+		if err != nil {
+			return err
+		}
+	}
+
+	if format == DumpFormatPlain {
+		psqlPath, err := version.Value.psql()
+		if err != nil {
+			return fmt.Errorf("error locating psql for v%s: %w", version.Value.version, err)
+		}
+
+		cmd := exec.Command(psqlPath, connString, "-f", dumpPath)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf(
+				"error running psql v%s command: %s",
+				version.Value.version, output,
+			)
+		}
+
+		return nil
+	}
+
+	// DumpFormatCustom/DumpFormatTar are restored from the extracted file
+	// directly; DumpFormatDirectory was tarred by dumpDirectory, so the
+	// extracted "dump.tar" must be unpacked into a directory first.
+	restorePath := dumpPath
+	if format == DumpFormatDirectory {
+		extractDir := filepath.Join(dir, "dump")
+		tarFile, err := os.Open(dumpPath)
+		if err != nil {
+			return fmt.Errorf("error opening dump archive: %w", err)
+		}
+		defer tarFile.Close()
+
+		if err := untarDirectory(tarFile, extractDir); err != nil {
+			return fmt.Errorf("error unpacking dump directory: %w", err)
+		}
+		restorePath = extractDir
+	}
+
+	return (Client{}).Restore(version, connString, RestoreParams{
+		DumpPath: restorePath,
+		Format:   format,
+	})
+}
+
+// readManifest decodes the manifest.json entry of a ZIP archive produced by
+// DumpZip.
+func readManifest(file *zip.File) (*Manifest, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error opening manifest.json in ZIP file: %w", err)
+	}
+	defer rc.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("error decoding manifest.json: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// decryptManifestedDump verifies the ciphertext at rawPath against manifest,
+// decrypts it per manifest.Encryption, verifies the resulting plaintext, and
+// writes it to dump.sql in dir. It returns the path to the verified
+// plaintext dump.
+func decryptManifestedDump(
+	dir, rawPath string, manifest *Manifest, opts RestoreZipOptions,
+) (string, error) {
+	raw, err := os.ReadFile(rawPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading dump: %w", err)
+	}
+
+	cipherSum := sha256.Sum256(raw)
+	if hex.EncodeToString(cipherSum[:]) != manifest.CiphertextSHA256 {
+		return "", fmt.Errorf(
+			"ciphertext checksum mismatch: archive may be corrupted or tampered with",
+		)
+	}
+
+	algo, err := parseEncryption(manifest.Encryption)
+	if err != nil {
+		return "", err
 	}
 
-	// Run the psql command to restore the database
-	cmd := exec.Command(version.Value.psql, connString, "-f", dumpPath)
+	decrypted, err := decryptStream(algo, opts.Passphrase, opts.AgeIdentity, bytes.NewReader(raw))
+	if err != nil {
+		return "", fmt.Errorf("error decrypting dump: %w", err)
+	}
+
+	plain, err := io.ReadAll(decrypted)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting dump: %w", err)
+	}
+
+	plainSum := sha256.Sum256(plain)
+	if hex.EncodeToString(plainSum[:]) != manifest.PlaintextSHA256 {
+		return "", fmt.Errorf("plaintext checksum mismatch after decrypting dump")
+	}
+
+	dumpPath := filepath.Join(dir, "dump.sql")
+	if err := os.WriteFile(dumpPath, plain, 0o600); err != nil {
+		return "", fmt.Errorf("error writing decrypted dump: %w", err)
+	}
+
+	return dumpPath, nil
+}
+
+// RestoreParams contains the parameters for the pg_restore command used by
+// Restore. DumpPath must point at a dump produced with a matching Format: a
+// single file for DumpFormatCustom/DumpFormatTar, or a directory for
+// DumpFormatDirectory.
+type RestoreParams struct {
+	// DumpPath is the file or directory pg_restore reads from.
+	DumpPath string
+
+	// Format is the format the dump at DumpPath was produced in.
+	// DumpFormatPlain is not supported here; use RestoreZip/psql instead.
+	Format DumpFormat
+
+	// Clean (--clean): drop database objects before recreating them.
+	Clean bool
+
+	// IfExists (--if-exists): use DROP ... IF EXISTS in --clean mode.
+	IfExists bool
+
+	// SchemaOnly (--schema-only): restore only the schema, not the data.
+	SchemaOnly bool
+
+	// DataOnly (--data-only): restore only the data, not the schema.
+	DataOnly bool
+
+	// Jobs (-j/--jobs): number of parallel restore workers. Only valid
+	// together with DumpFormatDirectory/DumpFormatCustom.
+	Jobs int
+
+	// Tables (--table), if non-empty, restores only the named tables.
+	Tables []string
+
+	// Schemas (--schema), if non-empty, restores only the named schemas.
+	Schemas []string
+}
+
+// Restore runs the pg_restore command to restore a dump produced by Dump in
+// DumpFormatCustom, DumpFormatDirectory, or DumpFormatTar. Plain-SQL dumps
+// are restored with psql via RestoreZip instead.
+func (Client) Restore(version PGVersion, connString string, params RestoreParams) error {
+	if params.Format == (DumpFormat{}) || params.Format == DumpFormatPlain {
+		return fmt.Errorf(
+			"pg_restore does not support DumpFormatPlain; restore it with psql instead",
+		)
+	}
+	if params.Jobs > 0 && params.Format != DumpFormatDirectory && params.Format != DumpFormatCustom {
+		return fmt.Errorf(
+			"RestoreParams.Jobs is only valid together with DumpFormatDirectory/DumpFormatCustom",
+		)
+	}
+
+	args := []string{"--dbname", connString}
+	if params.Clean {
+		args = append(args, "--clean")
+	}
+	if params.IfExists {
+		args = append(args, "--if-exists")
+	}
+	if params.SchemaOnly {
+		args = append(args, "--schema-only")
+	}
+	if params.DataOnly {
+		args = append(args, "--data-only")
+	}
+	if params.Jobs > 0 {
+		args = append(args, "--jobs", strconv.Itoa(params.Jobs))
+	}
+	for _, table := range params.Tables {
+		args = append(args, "--table", table)
+	}
+	for _, schema := range params.Schemas {
+		args = append(args, "--schema", schema)
+	}
+	args = append(args, params.DumpPath)
+
+	pgRestorePath, err := version.Value.pgRestore()
+	if err != nil {
+		return fmt.Errorf("error locating pg_restore for v%s: %w", version.Value.version, err)
+	}
+
+	cmd := exec.Command(pgRestorePath, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf(
-			"error running psql v%s command: %s",
-			version.Value.version, output,
+			"error running pg_restore v%s: %s", version.Value.version, output,
 		)
 	}
 
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDumpFormatNameParseDumpFormatRoundTrip(t *testing.T) {
+	formats := []DumpFormat{
+		DumpFormatPlain, DumpFormatCustom, DumpFormatDirectory, DumpFormatTar,
+	}
+
+	for _, format := range formats {
+		name := dumpFormatName(format)
+		got, err := parseDumpFormat(name)
+		if err != nil {
+			t.Fatalf("parseDumpFormat(%q): %v", name, err)
+		}
+		if got != format {
+			t.Errorf("parseDumpFormat(dumpFormatName(%v)) = %v, want %v", format, got, format)
+		}
+	}
+}
+
+func TestParseDumpFormatEmptyIsPlain(t *testing.T) {
+	format, err := parseDumpFormat("")
+	if err != nil {
+		t.Fatalf("parseDumpFormat(\"\"): %v", err)
+	}
+	if format != DumpFormatPlain {
+		t.Errorf("parseDumpFormat(\"\") = %v, want DumpFormatPlain", format)
+	}
+}
+
+func TestParseDumpFormatUnknown(t *testing.T) {
+	if _, err := parseDumpFormat("bson"); err == nil {
+		t.Fatal("expected an error for an unknown format name, got nil")
+	}
+}
+
+func TestDumpArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		params DumpParams
+		want   []string
+	}{
+		{
+			name:   "defaults to plain with no flags",
+			params: DumpParams{},
+			want:   []string{"postgres://example"},
+		},
+		{
+			name:   "plain format adds no -F flag",
+			params: DumpParams{Format: DumpFormatPlain},
+			want:   []string{"postgres://example"},
+		},
+		{
+			name:   "custom format adds -F c",
+			params: DumpParams{Format: DumpFormatCustom},
+			want:   []string{"postgres://example", "-F", "c"},
+		},
+		{
+			name:   "directory format adds -F d",
+			params: DumpParams{Format: DumpFormatDirectory},
+			want:   []string{"postgres://example", "-F", "d"},
+		},
+		{
+			name:   "jobs adds --jobs",
+			params: DumpParams{Format: DumpFormatDirectory, Jobs: 4},
+			want:   []string{"postgres://example", "-F", "d", "--jobs", "4"},
+		},
+		{
+			name:   "jobs without a parallel format is still passed through",
+			params: DumpParams{Jobs: 2},
+			want:   []string{"postgres://example", "--jobs", "2"},
+		},
+		{
+			name: "boolean flags combine with format",
+			params: DumpParams{
+				Format: DumpFormatTar, Clean: true, IfExists: true,
+			},
+			want: []string{
+				"postgres://example", "--clean", "--if-exists", "-F", "t",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dumpArgs("postgres://example", tt.params)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dumpArgs(%+v) = %v, want %v", tt.params, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDumpZipEntryName(t *testing.T) {
+	tests := []struct {
+		format DumpFormat
+		want   string
+	}{
+		{DumpFormatPlain, "dump.sql"},
+		{DumpFormat{}, "dump.sql"},
+		{DumpFormatCustom, "dump.dump"},
+		{DumpFormatDirectory, "dump.tar"},
+		{DumpFormatTar, "dump.tar"},
+	}
+
+	for _, tt := range tests {
+		got := dumpZipEntryName(tt.format)
+		if got != tt.want {
+			t.Errorf("dumpZipEntryName(%v) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
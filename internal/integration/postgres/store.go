@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// BackupStore abstracts the destination a dump is written to and read back
+// from. Implementations exist for the local filesystem and for S3-compatible,
+// GCS, and Azure Blob object storage, so Dump/Restore don't need to know
+// where a backup actually lives.
+type BackupStore interface {
+	// Put writes the content of r to key, creating or overwriting it.
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Get opens key for reading. The caller must close the returned
+	// ReadCloser.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// List returns the keys that start with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// Delete removes key. It is not an error for key to not exist.
+	Delete(ctx context.Context, key string) error
+}
+
+// safeJoin joins base and name the way filepath.Join would, but returns an
+// error if the result would escape base. It guards LocalStore and
+// untarDirectory against path traversal from a caller-supplied key or tar
+// entry name (e.g. "../../etc/passwd" or an absolute path) read back from
+// storage that isn't trusted to be free of tampering.
+func safeJoin(base, name string) (string, error) {
+	if filepath.IsAbs(name) {
+		return "", fmt.Errorf("path %q escapes %q", name, base)
+	}
+
+	joined := filepath.Join(base, name)
+	baseClean := filepath.Clean(base)
+
+	if joined != baseClean && !strings.HasPrefix(joined, baseClean+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes %q", name, base)
+	}
+
+	return joined, nil
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+)
+
+// AzureBlobStore is a BackupStore backed by an Azure Blob Storage container.
+type AzureBlobStore struct {
+	client    *azblob.Client
+	container string
+}
+
+// NewAzureBlobStore returns an AzureBlobStore that reads and writes blobs in
+// containerName through client.
+func NewAzureBlobStore(client *azblob.Client, containerName string) *AzureBlobStore {
+	return &AzureBlobStore{client: client, container: containerName}
+}
+
+// Put implements BackupStore.
+func (s *AzureBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	_, err := s.client.UploadStream(ctx, s.container, key, r, nil)
+	if err != nil {
+		return fmt.Errorf(
+			"error uploading %q to container %q: %w", key, s.container, err,
+		)
+	}
+	return nil
+}
+
+// Get implements BackupStore.
+func (s *AzureBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.DownloadStream(ctx, s.container, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error downloading %q from container %q: %w", key, s.container, err,
+		)
+	}
+	return out.Body, nil
+}
+
+// List implements BackupStore.
+func (s *AzureBlobStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+
+	pager := s.client.NewListBlobsFlatPager(s.container, &container.ListBlobsFlatOptions{
+		Prefix: &prefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error listing container %q with prefix %q: %w", s.container, prefix, err,
+			)
+		}
+		for _, blob := range page.Segment.BlobItems {
+			if blob.Name != nil {
+				keys = append(keys, *blob.Name)
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+// Delete implements BackupStore.
+func (s *AzureBlobStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteBlob(ctx, s.container, key, nil)
+	if err != nil && !bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return fmt.Errorf(
+			"error deleting %q from container %q: %w", key, s.container, err,
+		)
+	}
+	return nil
+}
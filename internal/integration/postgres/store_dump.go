@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// DumpToStore runs Dump and streams its output straight into store under
+// key, without ever touching local disk (except for DumpFormatDirectory,
+// which pg_dump itself requires a directory for).
+//
+// DumpToStore doesn't support DumpParams.Encryption: Dump always returns
+// the plaintext stream, and unlike DumpZip there's no manifest stored
+// alongside key to record how to decrypt it again. Use DumpZip/RestoreZip
+// instead if the dump needs to be encrypted at rest.
+func (c *Client) DumpToStore(
+	ctx context.Context, store BackupStore, key string,
+	version PGVersion, connString string, params ...DumpParams,
+) error {
+	pickedParams := DumpParams{}
+	if len(params) > 0 {
+		pickedParams = params[0]
+	}
+	if pickedParams.Encryption != EncryptionNone && pickedParams.Encryption != (Encryption{}) {
+		return fmt.Errorf(
+			"DumpToStore does not support DumpParams.Encryption; use DumpZip/RestoreZip instead",
+		)
+	}
+
+	if err := store.Put(ctx, key, c.Dump(version, connString, params...)); err != nil {
+		return fmt.Errorf("error storing dump under %q: %w", key, err)
+	}
+	return nil
+}
+
+// RestoreFromStore streams the dump stored under key back out of store and
+// pipes it into psql (DumpFormatPlain) or pg_restore (DumpFormatCustom/
+// DumpFormatTar), avoiding the "download whole dump to /tmp first" round
+// trip that RestoreZip needs for ZIP archives. DumpFormatDirectory is the
+// one exception: pg_restore requires a real directory for it, so the tar
+// stream is unpacked to a temporary directory before being restored.
+func (c *Client) RestoreFromStore(
+	ctx context.Context, store BackupStore, key string,
+	version PGVersion, connString string, format DumpFormat,
+) error {
+	reader, err := store.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("error reading dump %q from store: %w", key, err)
+	}
+	defer reader.Close()
+
+	if format == DumpFormatDirectory {
+		return c.restoreDirectoryFromStore(ctx, reader, version, connString)
+	}
+
+	var cmd *exec.Cmd
+	switch format {
+	case DumpFormatCustom, DumpFormatTar:
+		pgRestorePath, err := version.Value.pgRestore()
+		if err != nil {
+			return fmt.Errorf(
+				"error locating pg_restore for v%s: %w", version.Value.version, err,
+			)
+		}
+		cmd = exec.CommandContext(ctx, pgRestorePath, "--dbname", connString)
+	default:
+		psqlPath, err := version.Value.psql()
+		if err != nil {
+			return fmt.Errorf(
+				"error locating psql for v%s: %w", version.Value.version, err,
+			)
+		}
+		cmd = exec.CommandContext(ctx, psqlPath, connString)
+	}
+	cmd.Stdin = reader
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf(
+			"error restoring %q into v%s: %s", key, version.Value.version, output,
+		)
+	}
+
+	return nil
+}
+
+// restoreDirectoryFromStore unpacks the tar stream produced by
+// dumpDirectory into a temp directory and restores it with pg_restore.
+func (c *Client) restoreDirectoryFromStore(
+	ctx context.Context, r io.Reader, version PGVersion, connString string,
+) error {
+	dir, err := os.MkdirTemp("", "pbw-restore-*")
+	if err != nil {
+		return fmt.Errorf("error creating temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// tarDirectory names entries relative to the dump directory itself
+	// (e.g. "toc.dat"), so they must be unpacked into a "dump" subdirectory
+	// here, not dir itself, to match the DumpPath pg_restore is given below.
+	dumpDir := filepath.Join(dir, "dump")
+	if err := os.MkdirAll(dumpDir, 0o700); err != nil {
+		return fmt.Errorf("error creating dump directory: %w", err)
+	}
+
+	if err := untarDirectory(r, dumpDir); err != nil {
+		return fmt.Errorf("error unpacking dump directory: %w", err)
+	}
+
+	return c.Restore(version, connString, RestoreParams{
+		DumpPath: dumpDir,
+		Format:   DumpFormatDirectory,
+	})
+}
+
+// untarDirectory extracts a tar stream written by tarDirectory into dir. It
+// rejects any entry whose name would extract outside dir (tar-slip/path
+// traversal, e.g. "../../etc/cron.d/x" or an absolute path), since the
+// stream may come back out of a BackupStore that isn't trusted to be
+// free of tampering.
+func untarDirectory(r io.Reader, dir string) error {
+	tarReader := tar.NewReader(r)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		path, err := safeJoin(dir, header.Name)
+		if err != nil {
+			return fmt.Errorf("error extracting %q: %w", header.Name, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+
+		file, err := os.OpenFile(
+			path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode),
+		)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(file, tarReader); err != nil {
+			file.Close()
+			return err
+		}
+		file.Close()
+	}
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDumpToStoreRejectsEncryption guards against DumpToStore silently
+// storing a plaintext dump when the caller asked for one of the
+// DumpZip-only encryption algorithms: DumpToStore has no manifest to record
+// how to decrypt it again.
+func TestDumpToStoreRejectsEncryption(t *testing.T) {
+	err := (&Client{}).DumpToStore(
+		context.Background(), NewLocalStore(t.TempDir()), "dump.sql",
+		PG16, "postgres://example", DumpParams{Encryption: EncryptionAES256GCM},
+	)
+	if err == nil {
+		t.Fatal("expected an error for DumpParams.Encryption, got nil")
+	}
+}
+
+// TestTarDirectoryUntarDirectoryRoundTrip guards against the bug where
+// restoreDirectoryFromStore extracted tarDirectory's output into the
+// extraction root instead of a "dump" subdirectory, leaving the DumpPath
+// passed to Restore pointing at a directory that never existed.
+func TestTarDirectoryUntarDirectoryRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(srcDir, "toc.dat"), []byte("toc"), 0o600); err != nil {
+		t.Fatalf("error writing fixture file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(srcDir, "blobs"), 0o700); err != nil {
+		t.Fatalf("error creating fixture subdirectory: %v", err)
+	}
+	if err := os.WriteFile(
+		filepath.Join(srcDir, "blobs", "1.dat"), []byte("blob"), 0o600,
+	); err != nil {
+		t.Fatalf("error writing fixture file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tarDirectory(&buf, srcDir); err != nil {
+		t.Fatalf("tarDirectory: %v", err)
+	}
+
+	// restoreDirectoryFromStore unpacks into a "dump" subdirectory of its
+	// temp dir, not the temp dir itself, to match the DumpPath given to
+	// Restore. Reproduce that layout here rather than extracting into the
+	// TempDir root.
+	destRoot := t.TempDir()
+	dumpDir := filepath.Join(destRoot, "dump")
+	if err := os.MkdirAll(dumpDir, 0o700); err != nil {
+		t.Fatalf("error creating dump directory: %v", err)
+	}
+
+	if err := untarDirectory(&buf, dumpDir); err != nil {
+		t.Fatalf("untarDirectory: %v", err)
+	}
+
+	toc, err := os.ReadFile(filepath.Join(dumpDir, "toc.dat"))
+	if err != nil {
+		t.Fatalf("expected toc.dat under dumpDir: %v", err)
+	}
+	if string(toc) != "toc" {
+		t.Errorf("toc.dat content = %q, want %q", toc, "toc")
+	}
+
+	blob, err := os.ReadFile(filepath.Join(dumpDir, "blobs", "1.dat"))
+	if err != nil {
+		t.Fatalf("expected blobs/1.dat under dumpDir: %v", err)
+	}
+	if string(blob) != "blob" {
+		t.Errorf("blobs/1.dat content = %q, want %q", blob, "blob")
+	}
+
+	if _, err := os.Stat(filepath.Join(destRoot, "toc.dat")); !os.IsNotExist(err) {
+		t.Errorf("toc.dat leaked into destRoot instead of staying under dumpDir")
+	}
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// GCSStore is a BackupStore backed by a Google Cloud Storage bucket.
+type GCSStore struct {
+	client *storage.Client
+	bucket string
+}
+
+// NewGCSStore returns a GCSStore that reads and writes objects in bucket
+// through client.
+func NewGCSStore(client *storage.Client, bucket string) *GCSStore {
+	return &GCSStore{client: client, bucket: bucket}
+}
+
+// Put implements BackupStore.
+func (s *GCSStore) Put(ctx context.Context, key string, r io.Reader) error {
+	w := s.client.Bucket(s.bucket).Object(key).NewWriter(ctx)
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return fmt.Errorf("error writing %q to bucket %q: %w", key, s.bucket, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error finalizing %q in bucket %q: %w", key, s.bucket, err)
+	}
+	return nil
+}
+
+// Get implements BackupStore.
+func (s *GCSStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := s.client.Bucket(s.bucket).Object(key).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q from bucket %q: %w", key, s.bucket, err)
+	}
+	return r, nil
+}
+
+// List implements BackupStore.
+func (s *GCSStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+
+	it := s.client.Bucket(s.bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error listing bucket %q with prefix %q: %w", s.bucket, prefix, err,
+			)
+		}
+		keys = append(keys, attrs.Name)
+	}
+
+	return keys, nil
+}
+
+// Delete implements BackupStore.
+func (s *GCSStore) Delete(ctx context.Context, key string) error {
+	err := s.client.Bucket(s.bucket).Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("error deleting %q from bucket %q: %w", key, s.bucket, err)
+	}
+	return nil
+}
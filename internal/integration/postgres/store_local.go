@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore is a BackupStore backed by a directory on the local filesystem.
+// Keys may contain slashes; the corresponding subdirectories are created on
+// Put.
+type LocalStore struct {
+	baseDir string
+}
+
+// NewLocalStore returns a LocalStore rooted at baseDir. baseDir is created on
+// first use if it doesn't already exist.
+func NewLocalStore(baseDir string) *LocalStore {
+	return &LocalStore{baseDir: baseDir}
+}
+
+// path resolves key to its location under s.baseDir, rejecting any key
+// (e.g. containing "../" or an absolute path) that would escape it.
+func (s *LocalStore) path(key string) (string, error) {
+	return safeJoin(s.baseDir, filepath.FromSlash(key))
+}
+
+// Put implements BackupStore.
+func (s *LocalStore) Put(_ context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return fmt.Errorf("error resolving %q: %w", key, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("error creating store directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating %q: %w", key, err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, r); err != nil {
+		return fmt.Errorf("error writing %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// Get implements BackupStore.
+func (s *LocalStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving %q: %w", key, err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %q: %w", key, err)
+	}
+	return file, nil
+}
+
+// List implements BackupStore.
+func (s *LocalStore) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+
+	err := filepath.Walk(s.baseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(s.baseDir, path)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(relPath)
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing store: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Delete implements BackupStore.
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return fmt.Errorf("error resolving %q: %w", key, err)
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting %q: %w", key, err)
+	}
+	return nil
+}
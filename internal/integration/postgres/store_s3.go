@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Store is a BackupStore backed by an S3-compatible object store (AWS S3,
+// MinIO, Wasabi, ...). The client is expected to already be configured with
+// the target endpoint/credentials, so any S3-compatible provider works
+// without this package knowing about it.
+type S3Store struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewS3Store returns an S3Store that reads and writes objects in bucket
+// through client.
+func NewS3Store(client *s3.Client, bucket string) *S3Store {
+	return &S3Store{client: client, bucket: bucket}
+}
+
+// Put implements BackupStore.
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   r,
+	})
+	if err != nil {
+		return fmt.Errorf("error putting %q in bucket %q: %w", key, s.bucket, err)
+	}
+	return nil
+}
+
+// Get implements BackupStore.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting %q from bucket %q: %w", key, s.bucket, err)
+	}
+	return out.Body, nil
+}
+
+// List implements BackupStore.
+func (s *S3Store) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error listing bucket %q with prefix %q: %w", s.bucket, prefix, err,
+			)
+		}
+		for _, obj := range page.Contents {
+			if obj.Key != nil && strings.HasPrefix(*obj.Key, prefix) {
+				keys = append(keys, *obj.Key)
+			}
+		}
+	}
+
+	return keys, nil
+}
+
+// Delete implements BackupStore.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting %q from bucket %q: %w", key, s.bucket, err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import "testing"
+
+func TestSafeJoin(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		key     string
+		wantErr bool
+	}{
+		{name: "plain key", base: "/tmp/store", key: "dump.sql"},
+		{name: "nested key", base: "/tmp/store", key: "2026/07/dump.sql"},
+		{name: "dot-dot traversal", base: "/tmp/store", key: "../../etc/passwd", wantErr: true},
+		{name: "absolute path", base: "/tmp/store", key: "/etc/passwd", wantErr: true},
+		{name: "leading dot-dot disguised with subdir", base: "/tmp/store", key: "sub/../../escape", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := safeJoin(tt.base, tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("safeJoin(%q, %q) = %q, want error", tt.base, tt.key, path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("safeJoin(%q, %q) returned unexpected error: %v", tt.base, tt.key, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SchemaDiff contains the lines that differ between two schema-only dumps,
+// as returned by DiffSchema.
+type SchemaDiff struct {
+	// OnlyInA contains the lines present in the first dump but not the second.
+	OnlyInA []string
+
+	// OnlyInB contains the lines present in the second dump but not the
+	// first.
+	OnlyInB []string
+}
+
+// Equal reports whether the two schemas produced no differences.
+func (d *SchemaDiff) Equal() bool {
+	return len(d.OnlyInA) == 0 && len(d.OnlyInB) == 0
+}
+
+// Upgrade performs a controlled major-version upgrade of a database: it
+// dumps the source database with the fromVersion's pg_dump, verifies the
+// dump is well-formed, and restores it into the target instance with the
+// toVersion's psql. The source database is never written to, so a failure
+// at any step leaves it untouched. The upgrade is only considered
+// successful once a post-restore Ping against the target succeeds.
+func (c *Client) Upgrade(
+	fromVersion, toVersion PGVersion, fromConnString, toConnString string,
+) error {
+	dump, err := io.ReadAll(c.Dump(fromVersion, fromConnString))
+	if err != nil {
+		return fmt.Errorf(
+			"error dumping source database v%s: %w", fromVersion.Value.version, err,
+		)
+	}
+
+	if err := verifyDumpWellFormed(dump); err != nil {
+		return fmt.Errorf(
+			"dump from v%s failed validation: %w", fromVersion.Value.version, err,
+		)
+	}
+
+	dir, err := os.MkdirTemp("", "pbw-upgrade-*")
+	if err != nil {
+		return fmt.Errorf("error creating temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dumpPath := filepath.Join(dir, "dump.sql")
+	if err := os.WriteFile(dumpPath, dump, 0o600); err != nil {
+		return fmt.Errorf("error writing dump to disk: %w", err)
+	}
+
+	psqlPath, err := toVersion.Value.psql()
+	if err != nil {
+		return fmt.Errorf(
+			"error locating psql for v%s: %w", toVersion.Value.version, err,
+		)
+	}
+
+	cmd := exec.Command(psqlPath, toConnString, "-f", dumpPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf(
+			"error restoring dump into v%s: %s", toVersion.Value.version, output,
+		)
+	}
+
+	if err := (Client{}).Ping(toVersion, toConnString); err != nil {
+		return fmt.Errorf(
+			"restore into v%s finished but post-restore ping failed: %w",
+			toVersion.Value.version, err,
+		)
+	}
+
+	return nil
+}
+
+// verifyDumpWellFormed performs a cheap sanity check on a pg_dump output,
+// catching the common failure mode of a truncated or empty dump before it
+// is fed to psql/pg_restore.
+func verifyDumpWellFormed(dump []byte) error {
+	if len(dump) == 0 {
+		return fmt.Errorf("dump is empty")
+	}
+	if !bytes.Contains(dump, []byte("PostgreSQL database dump")) {
+		return fmt.Errorf("dump does not contain the expected pg_dump header")
+	}
+	if !bytes.Contains(dump, []byte("PostgreSQL database dump complete")) {
+		return fmt.Errorf("dump does not contain the pg_dump completion footer; it may be truncated")
+	}
+	return nil
+}
+
+// DiffSchema dumps the schema (--schema-only) of two databases, which may
+// be running different PostgreSQL major versions, and returns a structured
+// line-level diff. It's intended to let users validate an Upgrade before
+// cutting over traffic to the target database.
+func (c *Client) DiffSchema(
+	a, b PGVersion, connA, connB string,
+) (*SchemaDiff, error) {
+	schemaA, err := io.ReadAll(c.Dump(a, connA, DumpParams{SchemaOnly: true}))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error dumping schema for v%s: %w", a.Value.version, err,
+		)
+	}
+
+	schemaB, err := io.ReadAll(c.Dump(b, connB, DumpParams{SchemaOnly: true}))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error dumping schema for v%s: %w", b.Value.version, err,
+		)
+	}
+
+	return diffSchemaLines(schemaA, schemaB), nil
+}
+
+// diffSchemaLines returns the non-blank lines that are unique to each side.
+// Ordering differences (e.g. object dump order varying between versions)
+// are ignored; only set membership is compared.
+func diffSchemaLines(a, b []byte) *SchemaDiff {
+	setA := schemaLineSet(a)
+	setB := schemaLineSet(b)
+
+	diff := &SchemaDiff{}
+	for line := range setA {
+		if _, ok := setB[line]; !ok {
+			diff.OnlyInA = append(diff.OnlyInA, line)
+		}
+	}
+	for line := range setB {
+		if _, ok := setA[line]; !ok {
+			diff.OnlyInB = append(diff.OnlyInB, line)
+		}
+	}
+
+	sort.Strings(diff.OnlyInA)
+	sort.Strings(diff.OnlyInB)
+
+	return diff
+}
+
+func schemaLineSet(schema []byte) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, line := range strings.Split(string(schema), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		set[line] = struct{}{}
+	}
+	return set
+}
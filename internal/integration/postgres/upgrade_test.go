@@ -0,0 +1,42 @@
+package postgres
+
+import "testing"
+
+func TestDiffSchemaLines(t *testing.T) {
+	a := []byte("CREATE TABLE foo (id int);\n\nCREATE TABLE bar (id int);\n")
+	b := []byte("CREATE TABLE foo (id int);\nCREATE TABLE baz (id int);\n")
+
+	diff := diffSchemaLines(a, b)
+
+	if got, want := diff.OnlyInA, []string{"CREATE TABLE bar (id int);"}; !equalStrings(got, want) {
+		t.Errorf("OnlyInA = %v, want %v", got, want)
+	}
+	if got, want := diff.OnlyInB, []string{"CREATE TABLE baz (id int);"}; !equalStrings(got, want) {
+		t.Errorf("OnlyInB = %v, want %v", got, want)
+	}
+	if diff.Equal() {
+		t.Error("Equal() = true, want false")
+	}
+}
+
+func TestDiffSchemaLinesEqual(t *testing.T) {
+	a := []byte("CREATE TABLE foo (id int);\nCREATE TABLE bar (id int);\n")
+	b := []byte("CREATE TABLE bar (id int);\nCREATE TABLE foo (id int);\n")
+
+	diff := diffSchemaLines(a, b)
+	if !diff.Equal() {
+		t.Errorf("Equal() = false, want true (ordering should not matter); diff = %+v", diff)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
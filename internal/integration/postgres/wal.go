@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// safeWALToken matches the characters Configure/RestorePITR allow in a
+// prefix or in each word of archiverBinary before interpolating them into an
+// ALTER SYSTEM SQL literal or a postgresql.auto.conf value, both of which
+// are single-quoted with no escaping. archive_command/restore_command run
+// as the PostgreSQL server process, so a stray "'" or shell metacharacter
+// there would let a caller-supplied prefix break out of the literal and
+// inject SQL or rewrite the command PostgreSQL runs.
+var safeWALToken = regexp.MustCompile(`^[A-Za-z0-9_./-]+$`)
+
+// validateWALToken returns an error if value contains characters unsafe to
+// interpolate into the SQL/config strings built by Configure/RestorePITR.
+func validateWALToken(what, value string) error {
+	if !safeWALToken.MatchString(value) {
+		return fmt.Errorf("%s %q contains characters not safe to embed in SQL/config", what, value)
+	}
+	return nil
+}
+
+// WALArchiver ships WAL segments for a source database into a BackupStore,
+// alongside the logical dumps produced by Dump, and restores a base backup
+// plus replayed WAL up to a point in time. It gives a second-granularity
+// RPO between scheduled logical dumps.
+type WALArchiver struct {
+	store  BackupStore
+	prefix string
+}
+
+// NewWALArchiver returns a WALArchiver that stores WAL segments and base
+// backups for one source database under prefix in store. prefix should be
+// unique per source database (e.g. the connection's database ID).
+func NewWALArchiver(store BackupStore, prefix string) *WALArchiver {
+	return &WALArchiver{store: store, prefix: strings.TrimSuffix(prefix, "/")}
+}
+
+func (a *WALArchiver) walKey(filename string) string {
+	return path.Join(a.prefix, "wal", filename)
+}
+
+func (a *WALArchiver) basebackupKey(takenAt time.Time) string {
+	return path.Join(a.prefix, "basebackups", takenAt.UTC().Format(time.RFC3339)+".tar")
+}
+
+// ArchiveCommand returns the PostgreSQL archive_command to configure via
+// Configure. archiverBinary must be a path to a small helper that forwards
+// its "%p %f" arguments to PushWALSegment, e.g. `pgbackweb wal-push`.
+func (a *WALArchiver) ArchiveCommand(archiverBinary string) string {
+	return fmt.Sprintf("%s %s %%p %%f", archiverBinary, a.prefix)
+}
+
+// Configure turns on WAL archiving for connString by setting archive_mode
+// and archive_command via ALTER SYSTEM, then reloads the configuration.
+// archiverBinary is forwarded to ArchiveCommand.
+func (a *WALArchiver) Configure(
+	version PGVersion, connString string, archiverBinary string,
+) error {
+	// archiverBinary may carry a subcommand (e.g. "pgbackweb wal-push"), so
+	// each single-space-separated word is checked on its own.
+	for _, word := range strings.Split(archiverBinary, " ") {
+		if err := validateWALToken("archiverBinary", word); err != nil {
+			return err
+		}
+	}
+	if err := validateWALToken("prefix", a.prefix); err != nil {
+		return err
+	}
+
+	stmts := []string{
+		"ALTER SYSTEM SET wal_level = replica;",
+		"ALTER SYSTEM SET archive_mode = on;",
+		fmt.Sprintf(
+			"ALTER SYSTEM SET archive_command = '%s';",
+			a.ArchiveCommand(archiverBinary),
+		),
+		"SELECT pg_reload_conf();",
+	}
+
+	psqlPath, err := version.Value.psql()
+	if err != nil {
+		return fmt.Errorf("error locating psql for v%s: %w", version.Value.version, err)
+	}
+
+	cmd := exec.Command(psqlPath, connString, "-c", strings.Join(stmts, " "))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf(
+			"error configuring WAL archiving on v%s: %s", version.Value.version, output,
+		)
+	}
+
+	return nil
+}
+
+// PushWALSegment uploads the WAL segment at walPath (PostgreSQL's "%p") to
+// the store under its segment name walName ("%f"). It's the counterpart
+// invoked by the archive_command configured via Configure/ArchiveCommand.
+func (a *WALArchiver) PushWALSegment(ctx context.Context, walPath, walName string) error {
+	file, err := os.Open(walPath)
+	if err != nil {
+		return fmt.Errorf("error opening WAL segment %q: %w", walPath, err)
+	}
+	defer file.Close()
+
+	if err := a.store.Put(ctx, a.walKey(walName), file); err != nil {
+		return fmt.Errorf("error archiving WAL segment %q: %w", walName, err)
+	}
+
+	return nil
+}
+
+// BasebackupNow runs pg_basebackup against connString and streams the
+// resulting tar-format base backup into the store, returning the key it was
+// stored under. This is the base for point-in-time recovery: RestorePITR
+// lays this down before replaying WAL.
+func (a *WALArchiver) BasebackupNow(version PGVersion, connString string) (string, error) {
+	dir, err := os.MkdirTemp("", "pbw-basebackup-*")
+	if err != nil {
+		return "", fmt.Errorf("error creating temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pgBasebackupPath, err := version.Value.pgBasebackup()
+	if err != nil {
+		return "", fmt.Errorf(
+			"error locating pg_basebackup for v%s: %w", version.Value.version, err,
+		)
+	}
+
+	errBuf := &strings.Builder{}
+	cmd := exec.Command(
+		pgBasebackupPath,
+		"-D", dir,
+		"-F", "tar",
+		"-X", "stream",
+		"-d", connString,
+	)
+	cmd.Stderr = errBuf
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf(
+			"error running pg_basebackup v%s: %s", version.Value.version, errBuf.String(),
+		)
+	}
+
+	takenAt := time.Now()
+	key := a.basebackupKey(takenAt)
+
+	reader, writer := io.Pipe()
+	go func() {
+		defer writer.Close()
+		if err := tarDirectory(writer, dir); err != nil {
+			writer.CloseWithError(fmt.Errorf("error taring base backup: %w", err))
+		}
+	}()
+
+	if err := a.store.Put(context.Background(), key, reader); err != nil {
+		return "", fmt.Errorf("error storing base backup: %w", err)
+	}
+
+	return key, nil
+}
+
+// RestorePITR lays down the most recent base backup taken at or before
+// target, then configures recovery to replay archived WAL up to target.
+// dataDir must be an empty PostgreSQL data directory; bringing up the
+// server process to actually perform recovery is the caller's
+// responsibility, the same way Dump/Restore don't manage the server
+// lifecycle either.
+func (a *WALArchiver) RestorePITR(
+	ctx context.Context, version PGVersion, dataDir string, target time.Time,
+) error {
+	if err := validateWALToken("prefix", a.prefix); err != nil {
+		return err
+	}
+
+	key, err := a.latestBasebackupAtOrBefore(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	reader, err := a.store.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("error reading base backup %q: %w", key, err)
+	}
+	defer reader.Close()
+
+	if err := untarDirectory(reader, dataDir); err != nil {
+		return fmt.Errorf("error unpacking base backup %q: %w", key, err)
+	}
+
+	if err := os.WriteFile(
+		filepath.Join(dataDir, "recovery.signal"), nil, 0o600,
+	); err != nil {
+		return fmt.Errorf("error writing recovery.signal: %w", err)
+	}
+
+	restoreCmd := fmt.Sprintf(
+		"restore_command = 'pgbackweb wal-fetch %s %%f %%p'\n"+
+			"recovery_target_time = '%s'\n"+
+			"recovery_target_action = 'promote'\n",
+		a.prefix, target.UTC().Format("2006-01-02 15:04:05Z07:00"),
+	)
+	confPath := filepath.Join(dataDir, "postgresql.auto.conf")
+	confFile, err := os.OpenFile(confPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening %q: %w", confPath, err)
+	}
+	defer confFile.Close()
+
+	if _, err := confFile.WriteString(restoreCmd); err != nil {
+		return fmt.Errorf("error writing recovery settings to %q: %w", confPath, err)
+	}
+
+	return nil
+}
+
+// latestBasebackupAtOrBefore returns the store key of the most recent base
+// backup taken at or before target.
+func (a *WALArchiver) latestBasebackupAtOrBefore(
+	ctx context.Context, target time.Time,
+) (string, error) {
+	keys, err := a.store.List(ctx, path.Join(a.prefix, "basebackups")+"/")
+	if err != nil {
+		return "", fmt.Errorf("error listing base backups: %w", err)
+	}
+
+	sort.Strings(keys)
+
+	var best string
+	for _, key := range keys {
+		name := strings.TrimSuffix(filepath.Base(key), ".tar")
+		takenAt, err := time.Parse(time.RFC3339, name)
+		if err != nil {
+			continue
+		}
+		if takenAt.After(target) {
+			break
+		}
+		best = key
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("no base backup found at or before %s", target)
+	}
+
+	return best, nil
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchiveCommand(t *testing.T) {
+	archiver := NewWALArchiver(nil, "db-123")
+
+	got := archiver.ArchiveCommand("pgbackweb wal-push")
+	want := "pgbackweb wal-push db-123 %p %f"
+	if got != want {
+		t.Errorf("ArchiveCommand(%q) = %q, want %q", "pgbackweb wal-push", got, want)
+	}
+}
+
+func TestArchiveCommandTrimsTrailingSlashFromPrefix(t *testing.T) {
+	archiver := NewWALArchiver(nil, "db-123/")
+
+	got := archiver.ArchiveCommand("pgbackweb wal-push")
+	want := "pgbackweb wal-push db-123 %p %f"
+	if got != want {
+		t.Errorf("ArchiveCommand(%q) = %q, want %q", "pgbackweb wal-push", got, want)
+	}
+}
+
+// fakeListStore is a BackupStore that only implements List, for exercising
+// latestBasebackupAtOrBefore without a real backing store.
+type fakeListStore struct {
+	keys []string
+}
+
+func (s *fakeListStore) Put(context.Context, string, io.Reader) error { return nil }
+
+func (s *fakeListStore) Get(context.Context, string) (io.ReadCloser, error) {
+	return nil, nil
+}
+
+func (s *fakeListStore) List(_ context.Context, prefix string) ([]string, error) {
+	var matched []string
+	for _, key := range s.keys {
+		if strings.HasPrefix(key, prefix) {
+			matched = append(matched, key)
+		}
+	}
+	return matched, nil
+}
+
+func (s *fakeListStore) Delete(context.Context, string) error { return nil }
+
+func TestLatestBasebackupAtOrBeforePicksMostRecentNotAfterTarget(t *testing.T) {
+	store := &fakeListStore{keys: []string{
+		"db-1/basebackups/2026-01-01T00:00:00Z.tar",
+		"db-1/basebackups/2026-01-03T00:00:00Z.tar",
+		"db-1/basebackups/2026-01-02T00:00:00Z.tar",
+	}}
+	archiver := NewWALArchiver(store, "db-1")
+
+	target := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	got, err := archiver.latestBasebackupAtOrBefore(context.Background(), target)
+	if err != nil {
+		t.Fatalf("latestBasebackupAtOrBefore: %v", err)
+	}
+	if want := "db-1/basebackups/2026-01-02T00:00:00Z.tar"; got != want {
+		t.Errorf("latestBasebackupAtOrBefore(%v) = %q, want %q", target, got, want)
+	}
+}
+
+func TestLatestBasebackupAtOrBeforeIgnoresUnparseableKeys(t *testing.T) {
+	store := &fakeListStore{keys: []string{
+		"db-1/basebackups/not-a-timestamp.tar",
+		"db-1/basebackups/2026-01-01T00:00:00Z.tar",
+	}}
+	archiver := NewWALArchiver(store, "db-1")
+
+	target := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	got, err := archiver.latestBasebackupAtOrBefore(context.Background(), target)
+	if err != nil {
+		t.Fatalf("latestBasebackupAtOrBefore: %v", err)
+	}
+	if want := "db-1/basebackups/2026-01-01T00:00:00Z.tar"; got != want {
+		t.Errorf("latestBasebackupAtOrBefore(%v) = %q, want %q", target, got, want)
+	}
+}
+
+func TestLatestBasebackupAtOrBeforeNoBackupBeforeTarget(t *testing.T) {
+	store := &fakeListStore{keys: []string{
+		"db-1/basebackups/2026-01-05T00:00:00Z.tar",
+	}}
+	archiver := NewWALArchiver(store, "db-1")
+
+	target := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := archiver.latestBasebackupAtOrBefore(context.Background(), target)
+	if err == nil {
+		t.Fatal("expected an error when no base backup exists at or before target, got nil")
+	}
+}
+
+func TestValidateWALTokenRejectsQuotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "plain id", value: "db-123"},
+		{name: "path-like binary", value: "/usr/local/bin/pgbackweb"},
+		{name: "embedded single quote", value: "db'; DROP TABLE users; --", wantErr: true},
+		{name: "embedded space and shell metacharacter", value: "db; rm -rf /", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWALToken("prefix", tt.value)
+			if tt.wantErr && err == nil {
+				t.Fatalf("validateWALToken(%q) = nil, want error", tt.value)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("validateWALToken(%q) returned unexpected error: %v", tt.value, err)
+			}
+		})
+	}
+}